@@ -0,0 +1,230 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metric // import "go.opentelemetry.io/otel/sdk/metric"
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// This file implements just enough of semver 2.0.0 version comparison and
+// constraint matching to support NewView and NewViewRegexp's Scope.Version
+// criteria, without pulling a third-party dependency into this module's
+// production code path.
+
+// semverVersion is a parsed semver version. Build metadata is accepted but
+// not retained, since it is explicitly excluded from precedence by the
+// semver spec.
+type semverVersion struct {
+	major, minor, patch int
+	pre                 string
+}
+
+// versionRE matches an optional leading "v", a required major version, and
+// optional minor, patch, pre-release, and build components.
+var versionRE = regexp.MustCompile(`^v?(\d+)(?:\.(\d+)(?:\.(\d+))?)?(?:-([0-9A-Za-z.-]+))?(?:\+[0-9A-Za-z.-]+)?$`)
+
+func parseSemverVersion(s string) (semverVersion, error) {
+	m := versionRE.FindStringSubmatch(s)
+	if m == nil {
+		return semverVersion{}, fmt.Errorf("invalid version %q", s)
+	}
+	var v semverVersion
+	v.major, _ = strconv.Atoi(m[1])
+	if m[2] != "" {
+		v.minor, _ = strconv.Atoi(m[2])
+	}
+	if m[3] != "" {
+		v.patch, _ = strconv.Atoi(m[3])
+	}
+	v.pre = m[4]
+	return v, nil
+}
+
+// compare returns -1, 0, or 1 as a is less than, equal to, or greater than
+// b, following semver 2.0.0 precedence (a pre-release version has lower
+// precedence than the associated normal version).
+func (a semverVersion) compare(b semverVersion) int {
+	if c := intCompare(a.major, b.major); c != 0 {
+		return c
+	}
+	if c := intCompare(a.minor, b.minor); c != 0 {
+		return c
+	}
+	if c := intCompare(a.patch, b.patch); c != 0 {
+		return c
+	}
+	return comparePrerelease(a.pre, b.pre)
+}
+
+func intCompare(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// comparePrerelease implements semver 2.0.0 pre-release precedence: a
+// version without a pre-release outranks one with a pre-release, and two
+// pre-releases are compared identifier by dot-separated identifier.
+func comparePrerelease(a, b string) int {
+	switch {
+	case a == "" && b == "":
+		return 0
+	case a == "":
+		return 1
+	case b == "":
+		return -1
+	}
+
+	as := strings.Split(a, ".")
+	bs := strings.Split(b, ".")
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		if c := compareIdentifier(as[i], bs[i]); c != 0 {
+			return c
+		}
+	}
+	return intCompare(len(as), len(bs))
+}
+
+// compareIdentifier compares a single dot-separated pre-release
+// identifier. Numeric identifiers are compared numerically and always
+// have lower precedence than alphanumeric identifiers; otherwise
+// identifiers are compared lexically.
+func compareIdentifier(a, b string) int {
+	an, aErr := strconv.Atoi(a)
+	bn, bErr := strconv.Atoi(b)
+	switch {
+	case aErr == nil && bErr == nil:
+		return intCompare(an, bn)
+	case aErr == nil:
+		return -1
+	case bErr == nil:
+		return 1
+	default:
+		return strings.Compare(a, b)
+	}
+}
+
+// semverComparator is a single "<op><version>" clause of a
+// semverConstraint, e.g. ">=1.2.0".
+type semverComparator struct {
+	op string
+	v  semverVersion
+}
+
+func (c semverComparator) matches(v semverVersion) bool {
+	r := v.compare(c.v)
+	switch c.op {
+	case "=":
+		return r == 0
+	case "<":
+		return r < 0
+	case "<=":
+		return r <= 0
+	case ">":
+		return r > 0
+	case ">=":
+		return r >= 0
+	default:
+		return false
+	}
+}
+
+// semverConstraint is an ordered list of semverComparator clauses, all of
+// which must match (logical AND) for a version to satisfy the constraint.
+type semverConstraint []semverComparator
+
+// matches reports whether every comparator in c matches v.
+func (c semverConstraint) matches(v semverVersion) bool {
+	for _, cmp := range c {
+		if !cmp.matches(v) {
+			return false
+		}
+	}
+	return true
+}
+
+// exact reports whether c can only ever match a single, exact version
+// (i.e. it is not a range).
+func (c semverConstraint) exact() bool {
+	return len(c) == 1 && c[0].op == "="
+}
+
+// parseSemverConstraint parses a whitespace-separated list of comparator
+// clauses (e.g. ">=1.2.0 <2.0.0"), a tilde-range (e.g. "~1.4"), or a bare
+// version (e.g. "v0.1.0", equivalent to "=v0.1.0").
+func parseSemverConstraint(s string) (semverConstraint, error) {
+	fields := strings.Fields(s)
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("empty version constraint")
+	}
+
+	var out semverConstraint
+	for _, f := range fields {
+		if strings.HasPrefix(f, "~") {
+			cs, err := tildeRange(strings.TrimPrefix(f, "~"))
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, cs...)
+			continue
+		}
+
+		op, rest := splitComparatorOp(f)
+		v, err := parseSemverVersion(rest)
+		if err != nil {
+			return nil, fmt.Errorf("invalid version %q: %w", rest, err)
+		}
+		out = append(out, semverComparator{op: op, v: v})
+	}
+	return out, nil
+}
+
+func splitComparatorOp(s string) (op, rest string) {
+	for _, candidate := range []string{">=", "<=", ">", "<", "="} {
+		if strings.HasPrefix(s, candidate) {
+			return candidate, strings.TrimPrefix(s, candidate)
+		}
+	}
+	return "=", s
+}
+
+// tildeRange expands a tilde-range version (the part after "~") into the
+// [">=", "<"] comparator pair it is shorthand for: "~1" allows any 1.x.x
+// release, "~1.4" allows any 1.4.x release, and "~1.4.2" allows 1.4.2 and
+// any later 1.4.x release.
+func tildeRange(s string) (semverConstraint, error) {
+	v, err := parseSemverVersion(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid version %q: %w", s, err)
+	}
+
+	upper := semverVersion{major: v.major + 1}
+	if strings.Contains(s, ".") {
+		upper = semverVersion{major: v.major, minor: v.minor + 1}
+	}
+
+	return semverConstraint{
+		{op: ">=", v: v},
+		{op: "<", v: upper},
+	}, nil
+}
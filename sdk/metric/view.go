@@ -0,0 +1,361 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metric // import "go.opentelemetry.io/otel/sdk/metric"
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric/unit"
+	"go.opentelemetry.io/otel/sdk/instrumentation"
+	"go.opentelemetry.io/otel/sdk/metric/aggregation"
+)
+
+// errEmptyView is logged when a view does not have any criteria set and
+// therefore would match no instruments.
+var errEmptyView = errors.New("no criteria provided, view will match no instruments")
+
+// errMultiInst is logged when a view is configured with a Stream.Name while
+// its Instrument criteria matches more than one instrument, which would
+// produce multiple streams with the same name.
+var errMultiInst = errors.New("name replacement for multiple instruments")
+
+// emptyView is a View that does not match any instrument.
+func emptyView(Instrument) (Stream, bool) { return Stream{}, false }
+
+// InstrumentKind describes the kind of instrument a Meter can create.
+type InstrumentKind uint8
+
+const (
+	// instrumentKindUndefined is an undefined instrument kind, it should not
+	// be used by any initialized InstrumentKind.
+	instrumentKindUndefined InstrumentKind = iota // nolint:deadcode,varcheck,unused
+
+	// InstrumentKindSyncCounter identifies a group of instruments that
+	// record increasing values synchronously with the code path they are
+	// measuring.
+	InstrumentKindSyncCounter
+	// InstrumentKindSyncUpDownCounter identifies a group of instruments
+	// that record increasing and decreasing values synchronously with the
+	// code path they are measuring.
+	InstrumentKindSyncUpDownCounter
+	// InstrumentKindSyncHistogram identifies a group of instruments that
+	// record a distribution of values synchronously with the code path
+	// they are measuring.
+	InstrumentKindSyncHistogram
+	// InstrumentKindAsyncCounter identifies a group of instruments that
+	// record increasing values in an asynchronous callback.
+	InstrumentKindAsyncCounter
+	// InstrumentKindAsyncUpDownCounter identifies a group of instruments
+	// that record increasing and decreasing values in an asynchronous
+	// callback.
+	InstrumentKindAsyncUpDownCounter
+	// InstrumentKindAsyncGauge identifies a group of instruments that
+	// record current values in an asynchronous callback.
+	InstrumentKindAsyncGauge
+)
+
+// Instrument describes properties an instrument is created with, as well as
+// identifying information that can be used to match it with Views.
+type Instrument struct {
+	// Name is the human-readable identifier of the instrument.
+	Name string
+	// Description describes the purpose of the instrument.
+	Description string
+	// Kind defines the functional group of the instrument.
+	Kind InstrumentKind
+	// Unit is the unit of measurement recorded by the instrument.
+	Unit unit.Unit
+	// Scope identifies the instrumentation that created the instrument.
+	Scope instrumentation.Scope
+}
+
+// empty returns if all fields of i are their zero-value.
+func (i Instrument) empty() bool {
+	return i == Instrument{}
+}
+
+// Stream describes the stream of data an instrument produces.
+type Stream struct {
+	// Name is the human-readable identifier of the stream.
+	Name string
+	// Description describes the purpose of the data.
+	Description string
+	// Unit is the unit of measurement recorded.
+	Unit unit.Unit
+	// Aggregation the stream uses for an instrument.
+	Aggregation aggregation.Aggregation
+	// AttributeFilter is applied to all attributes recorded for an
+	// instrument. Only attributes for which this function returns true
+	// will be kept in the resulting stream.
+	AttributeFilter attribute.Filter
+}
+
+// View is a function that applies the Aggregation and attribute filter a
+// Reader will use for an Instrument. A View is registered with a
+// MeterProvider and is evaluated for each instrument an Meter creates. If
+// the View returns false, the Instrument is not transformed and the
+// default, Reader defined, aggregation is used.
+type View func(Instrument) (Stream, bool)
+
+// NewView returns a View that applies the Stream mask for all instruments
+// that match criteria. The returned View will only apply mask if all
+// non-zero-value fields of criteria match the corresponding Instrument
+// passed to the view. If no criteria are provided, a view that matches no
+// instruments is returned.
+//
+// The Name field of criteria supports wildcard pattern matching. The "*"
+// wildcard is recognized as matching zero or more characters, and "?" is
+// recognized as matching any single character. For example, a pattern of
+// "*" matches all instrument names.
+//
+// If mask's Aggregation is set, its Err method is checked after it is
+// copied. If that Err method returns a non-nil error, the Aggregation is
+// dropped and the error is handled with otel.Handle.
+//
+// The Scope.Name and Scope.SchemaURL fields of criteria also support
+// matching: Scope.Name uses the same wildcard grammar as Name, and
+// Scope.SchemaURL matches a prefix of the instrument's schema URL when it
+// ends in "*". Scope.Version is parsed as a semver constraint (e.g.
+// ">=1.2.0 <2.0.0" or "~1.4") rather than compared for exact equality; a
+// bare version such as "v0.1.0" is a valid constraint that only matches
+// that exact version, preserving the pre-constraint behavior. If
+// criteria.Scope.Version fails to parse as a constraint, the error is
+// handled with otel.Handle and the returned View matches no instruments.
+//
+// The Stream.Name from mask takes precedence over the name of the matched
+// Instrument. If Stream.Name is empty, the name from the matched instrument
+// is used. If the Name field of criteria contains wildcard characters, or
+// any of its Scope fields can themselves match more than one instrument
+// (a wildcard Scope.Name, a prefix Scope.SchemaURL, or a Scope.Version
+// constraint that is not an exact version), Stream.Name from mask must not
+// be used, doing so will return an emptyView (logged as an error).
+func NewView(criteria Instrument, mask Stream) View {
+	if criteria.empty() {
+		otel.Handle(errEmptyView)
+		return emptyView
+	}
+
+	var nameMatch func(string) bool
+	var nameAmbiguous bool
+	switch {
+	case criteria.Name == "":
+		nameMatch = func(string) bool { return true }
+	case strings.ContainsAny(criteria.Name, "*?"):
+		nameAmbiguous = true
+		nameMatch = globMatch(criteria.Name)
+	default:
+		name := criteria.Name
+		nameMatch = func(s string) bool { return s == name }
+	}
+
+	scopeMatch, scopeAmbiguous, err := newScopeMatcher(criteria.Scope)
+	if err != nil {
+		otel.Handle(err)
+		return emptyView
+	}
+
+	if mask.Name != "" && (nameAmbiguous || scopeAmbiguous) {
+		otel.Handle(errMultiInst)
+		return emptyView
+	}
+
+	return newView(criteria, mask, nameMatch, scopeMatch)
+}
+
+// newScopeMatcher returns a function reporting whether an
+// instrumentation.Scope matches criteria, and whether that criteria can
+// match more than one distinct Scope (e.g. a wildcard Scope.Name, a prefix
+// Scope.SchemaURL, or a Scope.Version range). Scope.Name uses the same
+// "*"/"?" glob grammar as an Instrument's Name, Scope.SchemaURL matches a
+// prefix when criteria ends in "*", and Scope.Version is compiled, once,
+// as a semver constraint. It returns an error if criteria.Version is not a
+// valid semver constraint.
+func newScopeMatcher(criteria instrumentation.Scope) (match func(instrumentation.Scope) bool, ambiguous bool, err error) {
+	nameMatch, nameAmbiguous := scopeNameMatch(criteria.Name)
+	urlMatch, urlAmbiguous := schemaURLMatch(criteria.SchemaURL)
+
+	versionMatch := func(string) bool { return true }
+	var versionAmbiguous bool
+	if criteria.Version != "" {
+		constraint, err := parseSemverConstraint(criteria.Version)
+		if err != nil {
+			return nil, false, fmt.Errorf("scope version constraint %q: %w", criteria.Version, err)
+		}
+		versionAmbiguous = !constraint.exact()
+		versionMatch = func(v string) bool {
+			ver, err := parseSemverVersion(v)
+			if err != nil {
+				return false
+			}
+			return constraint.matches(ver)
+		}
+	}
+
+	match = func(s instrumentation.Scope) bool {
+		return nameMatch(s.Name) && versionMatch(s.Version) && urlMatch(s.SchemaURL)
+	}
+	return match, nameAmbiguous || urlAmbiguous || versionAmbiguous, nil
+}
+
+// scopeNameMatch returns a function matching an instrumentation.Scope.Name
+// against criteria, supporting the same "*"/"?" glob grammar as Instrument
+// Name matching, and whether criteria can match more than one Scope.Name.
+func scopeNameMatch(criteria string) (match func(string) bool, ambiguous bool) {
+	switch {
+	case criteria == "":
+		return func(string) bool { return true }, false
+	case strings.ContainsAny(criteria, "*?"):
+		return globMatch(criteria), true
+	default:
+		return func(s string) bool { return s == criteria }, false
+	}
+}
+
+// schemaURLMatch returns a function matching an
+// instrumentation.Scope.SchemaURL against criteria, and whether criteria
+// can match more than one SchemaURL. A trailing "*" in criteria matches
+// any schema URL sharing that prefix; otherwise criteria must match
+// exactly.
+func schemaURLMatch(criteria string) (match func(string) bool, ambiguous bool) {
+	switch {
+	case criteria == "":
+		return func(string) bool { return true }, false
+	case strings.HasSuffix(criteria, "*"):
+		prefix := strings.TrimSuffix(criteria, "*")
+		return func(s string) bool { return strings.HasPrefix(s, prefix) }, true
+	default:
+		return func(s string) bool { return s == criteria }, false
+	}
+}
+
+// newView returns a View that matches an Instrument against criteria (using
+// nameMatch for the Instrument.Name field and scopeMatch for its Scope)
+// and, for every match, applies mask to produce the resulting Stream.
+func newView(criteria Instrument, mask Stream, nameMatch func(string) bool, scopeMatch func(instrumentation.Scope) bool) View {
+	return func(i Instrument) (Stream, bool) {
+		if !nameMatch(i.Name) {
+			return Stream{}, false
+		}
+		if criteria.Description != "" && criteria.Description != i.Description {
+			return Stream{}, false
+		}
+		if criteria.Kind != 0 && criteria.Kind != i.Kind {
+			return Stream{}, false
+		}
+		if criteria.Unit != "" && criteria.Unit != i.Unit {
+			return Stream{}, false
+		}
+		if !scopeMatch(i.Scope) {
+			return Stream{}, false
+		}
+
+		stream := Stream{
+			Name:            i.Name,
+			Description:     i.Description,
+			Unit:            i.Unit,
+			Aggregation:     mask.Aggregation,
+			AttributeFilter: mask.AttributeFilter,
+		}
+		if mask.Name != "" {
+			stream.Name = mask.Name
+		}
+		if mask.Description != "" {
+			stream.Description = mask.Description
+		}
+		if mask.Unit != "" {
+			stream.Unit = mask.Unit
+		}
+		if stream.Aggregation != nil {
+			stream.Aggregation = stream.Aggregation.Copy()
+			if err := stream.Aggregation.Err(); err != nil {
+				stream.Aggregation = nil
+				otel.Handle(err)
+			}
+		}
+		return stream, true
+	}
+}
+
+// globMatch returns a function that reports whether a string matches the
+// glob pattern. The pattern recognizes "*" as matching zero or more
+// characters, and "?" as matching exactly one character. All other regular
+// expression meta-characters in pattern are treated literally.
+func globMatch(pattern string) func(string) bool {
+	quoted := regexp.QuoteMeta(pattern)
+	replacer := strings.NewReplacer(`\*`, ".*", `\?`, ".")
+	re := regexp.MustCompile("^" + replacer.Replace(quoted) + "$")
+	return re.MatchString
+}
+
+// NewViewRegexp returns a View that applies the Stream mask for all
+// instruments whose Name matches the RE2 regular expression held in
+// criteria.Name. Unlike NewView, which only supports the "*"/"?" glob
+// grammar, NewViewRegexp allows the full regexp.Compile syntax, including
+// capture groups and alternation. All other fields of criteria are matched
+// using the same rules as NewView.
+//
+// criteria.Name is compiled once, implicitly anchored with "^(?:" and ")$"
+// so that, as with NewView, the pattern must match the whole instrument
+// name rather than a substring of it. If criteria.Name fails to compile,
+// the error is handled with otel.Handle and the returned View matches no
+// instruments.
+//
+// As with NewView, Stream.Name from mask cannot be used when criteria may
+// match more than one instrument, whether because of criteria.Name or
+// because of a wildcard Scope.Name, a prefix Scope.SchemaURL, or a
+// Scope.Version range. Because that cannot be decided in general for an
+// arbitrary regular expression, NewViewRegexp only allows Stream.Name to be
+// set when criteria.Name contains no regular expression meta-characters
+// (i.e. it is equivalent to an exact match). Any other combination returns
+// an emptyView (logged as an error).
+func NewViewRegexp(criteria Instrument, mask Stream) View {
+	if criteria.empty() {
+		otel.Handle(errEmptyView)
+		return emptyView
+	}
+
+	var nameMatch func(string) bool
+	var nameAmbiguous bool
+	switch {
+	case criteria.Name == "":
+		nameMatch = func(string) bool { return true }
+	default:
+		re, err := regexp.Compile("^(?:" + criteria.Name + ")$")
+		if err != nil {
+			otel.Handle(err)
+			return emptyView
+		}
+		nameAmbiguous = regexp.QuoteMeta(criteria.Name) != criteria.Name
+		nameMatch = re.MatchString
+	}
+
+	scopeMatch, scopeAmbiguous, err := newScopeMatcher(criteria.Scope)
+	if err != nil {
+		otel.Handle(err)
+		return emptyView
+	}
+
+	if mask.Name != "" && (nameAmbiguous || scopeAmbiguous) {
+		otel.Handle(errMultiInst)
+		return emptyView
+	}
+
+	return newView(criteria, mask, nameMatch, scopeMatch)
+}
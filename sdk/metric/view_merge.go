@@ -0,0 +1,139 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metric // import "go.opentelemetry.io/otel/sdk/metric"
+
+import (
+	"fmt"
+	"reflect"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// errConflictingAggregation is logged when two Views that both match the
+// same Instrument each set a non-nil, different Aggregation. The later
+// View (in the order passed to MergeViews) wins.
+var errConflictingAggregation = fmt.Errorf("conflicting Aggregation from merged views, using the last one")
+
+// MergeViews returns a View that evaluates every one of views against an
+// Instrument and, for each that matches, merges their Streams into a
+// single result instead of using only the first match. Fields are merged
+// with later views taking precedence: a later Stream's non-zero Name,
+// Description, or Unit overrides an earlier one, and a later non-nil
+// Aggregation replaces an earlier non-nil Aggregation (logging a warning
+// via otel.Handle when the two differ, since the two Views then disagree
+// on how the instrument should be aggregated). AttributeFilter functions are composed with a
+// logical AND, so an attribute is kept only if every matching View's
+// filter (that set one) keeps it.
+//
+// The returned View matches an Instrument if, and only if, at least one of
+// views does.
+func MergeViews(views ...View) View {
+	return func(i Instrument) (Stream, bool) {
+		var (
+			merged  Stream
+			matched bool
+		)
+		for _, v := range views {
+			s, ok := v(i)
+			if !ok {
+				continue
+			}
+			if !matched {
+				merged, matched = s, true
+				continue
+			}
+			merged = mergeStream(merged, s)
+		}
+		return merged, matched
+	}
+}
+
+// mergeStream merges next into acc, with next's non-zero fields taking
+// precedence.
+func mergeStream(acc, next Stream) Stream {
+	out := acc
+	if next.Name != "" {
+		out.Name = next.Name
+	}
+	if next.Description != "" {
+		out.Description = next.Description
+	}
+	if next.Unit != "" {
+		out.Unit = next.Unit
+	}
+	if next.Aggregation != nil {
+		if out.Aggregation != nil && !reflect.DeepEqual(out.Aggregation, next.Aggregation) {
+			otel.Handle(errConflictingAggregation)
+		}
+		out.Aggregation = next.Aggregation
+	}
+	out.AttributeFilter = andFilter(out.AttributeFilter, next.AttributeFilter)
+	return out
+}
+
+// andFilter returns an attribute.Filter that keeps an attribute.KeyValue
+// only if both a and b keep it. A nil filter is treated as keeping
+// everything, so andFilter(nil, b) is b and andFilter(a, nil) is a.
+func andFilter(a, b attribute.Filter) attribute.Filter {
+	switch {
+	case a == nil:
+		return b
+	case b == nil:
+		return a
+	default:
+		return func(kv attribute.KeyValue) bool { return a(kv) && b(kv) }
+	}
+}
+
+// MatchResult is the outcome of evaluating a single View, at Index in a
+// ViewSet, against an Instrument.
+type MatchResult struct {
+	// Index is the View's position in the ViewSet, in the order the Views
+	// were passed to NewViewSet.
+	Index int
+	// Matched reports whether the View matched the Instrument.
+	Matched bool
+	// Stream is the View's output Stream. It is the zero-value Stream when
+	// Matched is false.
+	Stream Stream
+}
+
+// ViewSet is an ordered set of Views, built with NewViewSet. It exists for
+// diagnostic tooling: ViewSet.Explain reports, for a given Instrument, how
+// each View in the set handled it. A ViewSet is not created or filled by a
+// MeterProvider; it holds only the Views passed to NewViewSet.
+type ViewSet struct {
+	views []View
+}
+
+// NewViewSet returns a ViewSet recording views in the order given.
+func NewViewSet(views ...View) ViewSet {
+	return ViewSet{views: views}
+}
+
+// Explain returns the MatchResult of every View in vs against i, in
+// registration order.
+func (vs ViewSet) Explain(i Instrument) []MatchResult {
+	results := make([]MatchResult, len(vs.views))
+	for idx, v := range vs.views {
+		s, matched := v(i)
+		if !matched {
+			s = Stream{}
+		}
+		results[idx] = MatchResult{Index: idx, Matched: matched, Stream: s}
+	}
+	return results
+}
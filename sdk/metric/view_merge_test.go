@@ -0,0 +1,116 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metric // import "go.opentelemetry.io/otel/sdk/metric"
+
+import (
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/go-logr/logr/testr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/metric/aggregation"
+)
+
+func TestMergeViewsPrecedence(t *testing.T) {
+	first := NewView(Instrument{Name: "foo"}, Stream{Name: "first", Description: "first desc"})
+	second := NewView(Instrument{Name: "foo"}, Stream{Name: "second"})
+
+	got, matches := MergeViews(first, second)(Instrument{Name: "foo"})
+	require.True(t, matches)
+	assert.Equal(t, "second", got.Name, "later view should override Name")
+	assert.Equal(t, "first desc", got.Description, "earlier view's Description should survive untouched")
+}
+
+func TestMergeViewsNoMatch(t *testing.T) {
+	noMatch := NewView(Instrument{Name: "bar"}, Stream{})
+	_, matches := MergeViews(noMatch)(Instrument{Name: "foo"})
+	assert.False(t, matches)
+}
+
+func TestMergeViewsSingleMatch(t *testing.T) {
+	noMatch := NewView(Instrument{Name: "bar"}, Stream{})
+	match := NewView(Instrument{Name: "foo"}, Stream{Name: "renamed"})
+
+	got, matches := MergeViews(noMatch, match)(Instrument{Name: "foo"})
+	require.True(t, matches)
+	assert.Equal(t, "renamed", got.Name)
+}
+
+func TestMergeViewsAttributeFilterComposes(t *testing.T) {
+	keep := attribute.String("keep", "yes")
+	drop := attribute.String("keep", "no")
+
+	first := NewView(Instrument{Name: "foo"}, Stream{
+		AttributeFilter: func(kv attribute.KeyValue) bool { return kv.Value.AsString() != "no" },
+	})
+	second := NewView(Instrument{Name: "foo"}, Stream{
+		AttributeFilter: func(kv attribute.KeyValue) bool { return kv.Key == "keep" },
+	})
+
+	got, matches := MergeViews(first, second)(Instrument{Name: "foo"})
+	require.True(t, matches)
+	require.NotNil(t, got.AttributeFilter)
+	assert.True(t, got.AttributeFilter(keep), "attribute allowed by both filters should be kept")
+	assert.False(t, got.AttributeFilter(drop), "attribute rejected by either filter should be dropped")
+}
+
+func TestMergeViewsConflictingAggregationLogged(t *testing.T) {
+	tLog := testr.NewWithOptions(t, testr.Options{Verbosity: 6})
+	l := &logCounter{LogSink: tLog.GetSink()}
+	otel.SetLogger(logr.New(l))
+
+	first := NewView(Instrument{Name: "foo"}, Stream{Aggregation: aggregation.Sum{}})
+	second := NewView(Instrument{Name: "foo"}, Stream{Aggregation: aggregation.LastValue{}})
+
+	got, matches := MergeViews(first, second)(Instrument{Name: "foo"})
+	require.True(t, matches)
+	assert.Equal(t, aggregation.LastValue{}, got.Aggregation)
+	assert.Equal(t, 1, l.ErrorN())
+}
+
+func TestMergeViewsBadAggregationDoesNotPoisonResult(t *testing.T) {
+	tLog := testr.NewWithOptions(t, testr.Options{Verbosity: 6})
+	l := &logCounter{LogSink: tLog.GetSink()}
+	otel.SetLogger(logr.New(l))
+
+	bad := NewView(Instrument{Name: "foo"}, Stream{Aggregation: badAgg{err: assert.AnError}})
+	good := NewView(Instrument{Name: "foo"}, Stream{Name: "renamed"})
+
+	got, matches := MergeViews(bad, good)(Instrument{Name: "foo"})
+	require.True(t, matches)
+	assert.Nil(t, got.Aggregation, "erroring aggregation from one view should not surface")
+	assert.Equal(t, "renamed", got.Name)
+	assert.Equal(t, 1, l.ErrorN())
+}
+
+func TestViewSetExplain(t *testing.T) {
+	first := NewView(Instrument{Name: "foo"}, Stream{Name: "first"})
+	second := NewView(Instrument{Name: "bar"}, Stream{})
+
+	vs := NewViewSet(first, second)
+	results := vs.Explain(Instrument{Name: "foo"})
+	require.Len(t, results, 2)
+
+	assert.Equal(t, 0, results[0].Index)
+	assert.True(t, results[0].Matched)
+	assert.Equal(t, "first", results[0].Stream.Name)
+
+	assert.Equal(t, 1, results[1].Index)
+	assert.False(t, results[1].Matched)
+}
@@ -252,6 +252,61 @@ func TestNewViewMatch(t *testing.T) {
 				{Scope: scope("", "", "https://go.dev")},
 			},
 		},
+		{
+			name:     "ScopeNameWildcard",
+			criteria: Instrument{Scope: scope("TestNewView*", "", "")},
+			matches: []Instrument{
+				{Scope: scope("TestNewViewMatch", "", "")},
+				{Scope: scope("TestNewView", "", "")},
+				completeIP,
+			},
+			notMatches: []Instrument{
+				{},
+				{Scope: scope("PrefixTestNewView", "", "")},
+				{Scope: scope("alt", "", "")},
+			},
+		},
+		{
+			name:     "ScopeSchemaURLPrefix",
+			criteria: Instrument{Scope: scope("", "", "https://opentelemetry.io/schemas/*")},
+			matches: []Instrument{
+				{Scope: scope("", "", schemaURL)},
+				{Scope: scope("", "", "https://opentelemetry.io/schemas/1.1.0")},
+				completeIP,
+			},
+			notMatches: []Instrument{
+				{},
+				{Scope: scope("", "", "https://opentelemetry.io/other")},
+				{Scope: scope("", "", "https://go.dev")},
+			},
+		},
+		{
+			name:     "ScopeVersionRange",
+			criteria: Instrument{Scope: scope("", ">=0.1.0 <0.2.0", "")},
+			matches: []Instrument{
+				{Scope: scope("", "v0.1.0", "")},
+				{Scope: scope("", "v0.1.9", "")},
+				completeIP,
+			},
+			notMatches: []Instrument{
+				{},
+				{Scope: scope("", "v0.2.0", "")},
+				{Scope: scope("", "v0.1.0-RC1", "")},
+			},
+		},
+		{
+			name:     "ScopeVersionTilde",
+			criteria: Instrument{Scope: scope("", "~1.4", "")},
+			matches: []Instrument{
+				{Scope: scope("", "v1.4.0", "")},
+				{Scope: scope("", "v1.4.9", "")},
+			},
+			notMatches: []Instrument{
+				{},
+				{Scope: scope("", "v1.5.0", "")},
+				{Scope: scope("", "v1.3.0", "")},
+			},
+		},
 		{
 			name:     "Scope",
 			criteria: Instrument{Scope: scope("TestNewViewMatch", "v0.1.0", schemaURL)},
@@ -341,6 +396,61 @@ func TestNewViewMatch(t *testing.T) {
 	}
 }
 
+func TestNewViewScopeVersionConstraintErrorLogged(t *testing.T) {
+	tLog := testr.NewWithOptions(t, testr.Options{Verbosity: 6})
+	l := &logCounter{LogSink: tLog.GetSink()}
+	otel.SetLogger(logr.New(l))
+
+	v := NewView(Instrument{Scope: scope("", "not a constraint", "")}, Stream{})
+	_, matches := v(completeIP)
+	assert.False(t, matches, "invalid scope version constraint should produce an emptyView")
+	assert.Equal(t, 1, l.ErrorN())
+}
+
+func TestNewViewScopeAmbiguityMultiInst(t *testing.T) {
+	tests := []struct {
+		name     string
+		criteria Instrument
+	}{
+		{
+			name:     "WildcardScopeName",
+			criteria: Instrument{Name: "foo", Scope: scope("net/http*", "", "")},
+		},
+		{
+			name:     "PrefixSchemaURL",
+			criteria: Instrument{Name: "foo", Scope: scope("", "", "https://opentelemetry.io/schemas/*")},
+		},
+		{
+			name:     "VersionRange",
+			criteria: Instrument{Name: "foo", Scope: scope("", ">=1.0.0 <2.0.0", "")},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			tLog := testr.NewWithOptions(t, testr.Options{Verbosity: 6})
+			l := &logCounter{LogSink: tLog.GetSink()}
+			otel.SetLogger(logr.New(l))
+
+			v := NewView(test.criteria, Stream{Name: "renamed"})
+			_, matches := v(Instrument{Name: "foo", Scope: scope("net/http.Server", "v1.4.0", schemaURL)})
+			assert.False(t, matches, "view matched despite Name mask ambiguity from Scope criteria")
+			assert.Equal(t, 1, l.ErrorN())
+		})
+	}
+
+	t.Run("Regexp/WildcardScopeName", func(t *testing.T) {
+		tLog := testr.NewWithOptions(t, testr.Options{Verbosity: 6})
+		l := &logCounter{LogSink: tLog.GetSink()}
+		otel.SetLogger(logr.New(l))
+
+		v := NewViewRegexp(Instrument{Name: "foo", Scope: scope("net/http*", "", "")}, Stream{Name: "renamed"})
+		_, matches := v(Instrument{Name: "foo", Scope: scope("net/http.Server", "", "")})
+		assert.False(t, matches, "regexp view matched despite Name mask ambiguity from Scope criteria")
+		assert.Equal(t, 1, l.ErrorN())
+	})
+}
+
 func TestNewViewReplace(t *testing.T) {
 	alt := "alternative value"
 	tests := []struct {
@@ -448,6 +558,91 @@ func TestNewViewReplace(t *testing.T) {
 	})
 }
 
+func testNewViewMatchNameRegexp() func(t *testing.T) {
+	tests := []struct {
+		name     string
+		criteria string
+		match    []string
+		notMatch []string
+	}{
+		{
+			name:     "Exact",
+			criteria: "foo",
+			match:    []string{"foo"},
+			notMatch: []string{"", "bar", "foobar", "barfoo"},
+		},
+		{
+			name:     "Anchoring",
+			criteria: "foo.*",
+			match:    []string{"foo", "foobar", "foo123"},
+			notMatch: []string{"", "barfoo", "1foo"},
+		},
+		{
+			name:     "Alternation",
+			criteria: "foo|bar",
+			match:    []string{"foo", "bar"},
+			notMatch: []string{"", "foobar", "baz"},
+		},
+		{
+			name:     "CaptureGroup",
+			criteria: "(foo)(bar)?",
+			match:    []string{"foo", "foobar"},
+			notMatch: []string{"", "bar", "foobaz"},
+		},
+	}
+
+	return func(t *testing.T) {
+		for _, test := range tests {
+			v := NewViewRegexp(Instrument{Name: test.criteria}, Stream{})
+			t.Run(test.name, func(t *testing.T) {
+				for _, n := range test.match {
+					_, matches := v(Instrument{Name: n})
+					assert.Truef(t, matches, "%s does not match %s", test.criteria, n)
+				}
+				for _, n := range test.notMatch {
+					_, matches := v(Instrument{Name: n})
+					assert.Falsef(t, matches, "%s matches %s", test.criteria, n)
+				}
+			})
+		}
+	}
+}
+
+func TestNewViewRegexpMatch(t *testing.T) {
+	// Avoid boilerplate for name match testing.
+	t.Run("Name", testNewViewMatchNameRegexp())
+
+	t.Run("NameMaskSingleInst", func(t *testing.T) {
+		// criteria.Name has no regexp meta-characters, so it is an exact
+		// match and Stream.Name is allowed.
+		got, matches := NewViewRegexp(Instrument{Name: "foo"}, Stream{Name: "bar"})(Instrument{Name: "foo"})
+		require.True(t, matches, "view did not match exact criteria")
+		assert.Equal(t, "bar", got.Name)
+	})
+
+	t.Run("NameMaskMultiInst", func(t *testing.T) {
+		tLog := testr.NewWithOptions(t, testr.Options{Verbosity: 6})
+		l := &logCounter{LogSink: tLog.GetSink()}
+		otel.SetLogger(logr.New(l))
+
+		v := NewViewRegexp(Instrument{Name: "foo.*"}, Stream{Name: "bar"})
+		_, matches := v(Instrument{Name: "foobar"})
+		assert.False(t, matches, "view matched despite Name mask ambiguity")
+		assert.Equal(t, 1, l.ErrorN())
+	})
+
+	t.Run("InvalidRegexp", func(t *testing.T) {
+		tLog := testr.NewWithOptions(t, testr.Options{Verbosity: 6})
+		l := &logCounter{LogSink: tLog.GetSink()}
+		otel.SetLogger(logr.New(l))
+
+		v := NewViewRegexp(Instrument{Name: "foo("}, Stream{})
+		_, matches := v(Instrument{Name: "foo("})
+		assert.False(t, matches, "invalid regexp should produce an emptyView")
+		assert.Equal(t, 1, l.ErrorN())
+	})
+}
+
 type badAgg struct {
 	aggregation.Aggregation
 	err error
@@ -0,0 +1,372 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package viewpipeline lets a View be built by declaratively chaining
+// classify, compose, and select stages instead of hand writing a closure
+// for every rule a MeterProvider needs to apply.
+//
+// A Pipeline first runs a matched Instrument through its Selectors, then
+// through its Classifiers (which annotate the Instrument with a Labels
+// bag), and finally through its Composer (which renders the Labels, via
+// Go templates, into the Stream a metric.View returns).
+package viewpipeline // import "go.opentelemetry.io/otel/sdk/metric/viewpipeline"
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"text/template"
+	"text/template/parse"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/aggregation"
+)
+
+// Labels is the bag of metadata Classifiers annotate an Instrument with as
+// it moves through a Pipeline. Composer templates reference these keys
+// directly (e.g. "{{.tier}}").
+type Labels map[string]string
+
+// Clone returns a copy of l that a Classifier can mutate without affecting
+// the Labels passed to earlier stages.
+func (l Labels) Clone() Labels {
+	out := make(Labels, len(l))
+	for k, v := range l {
+		out[k] = v
+	}
+	return out
+}
+
+// Selector reports whether an Instrument should continue through a
+// Pipeline. If any Selector returns false for an Instrument, the Pipeline's
+// View does not match it and evaluation stops before any Classifier runs.
+type Selector func(metric.Instrument) bool
+
+// Classifier annotates an Instrument with Labels, given the Labels produced
+// by the Classifiers run before it in the same Pipeline. A Classifier must
+// declare the label Keys it may set so Pipeline construction can validate
+// Composer templates against them.
+type Classifier struct {
+	// Keys are the Labels keys Classify may set. New rejects a Composer
+	// template that references a key no Classifier declares.
+	Keys []string
+	// Classify returns the Labels to use for the rest of the Pipeline,
+	// typically the received Labels with one or more Keys added.
+	Classify func(metric.Instrument, Labels) Labels
+}
+
+// Composer builds the Stream a matched, classified Instrument is
+// transformed into. Each template is parsed once, by New, and executed
+// against a view combining the Instrument (under the "Instrument" key) and
+// the Labels a Pipeline's Classifiers produced. NameTemplate and
+// DescriptionTemplate render directly to the Stream field of the same
+// name.
+//
+// AggregationTemplate currently only selects between the Reader's default
+// aggregation and dropping the stream: it must render to "", "default", or
+// "drop", and any other value is treated as a compose error and logged via
+// otel.Handle. Selecting a parameterized aggregation such as
+// aggregation.ExplicitBucketHistogram with classifier-chosen bucket
+// boundaries (e.g. to "pick histogram buckets" per a cardinality or unit
+// label) is not yet supported; AggregationTemplate can only pick from the
+// fixed names above, not construct an aggregation.Aggregation value.
+type Composer struct {
+	NameTemplate        string
+	DescriptionTemplate string
+	AggregationTemplate string
+}
+
+// Pipeline is a declarative View built from an ordered list of Selector
+// and Classifier stages and a single Composer stage.
+type Pipeline struct {
+	selectors   []Selector
+	classifiers []Classifier
+
+	composer Composer
+	nameTmpl *template.Template
+	descTmpl *template.Template
+	aggTmpl  *template.Template
+}
+
+// errUndefinedLabel is returned by New when a Composer template references
+// a Labels key that no Classifier declares.
+var errUndefinedLabel = errors.New("viewpipeline: template references undefined label")
+
+// New builds a Pipeline from selectors, classifiers, and composer.
+// Classifiers run in the order given, each receiving the Labels produced by
+// the one before it. New parses composer's templates immediately and
+// returns an error if any fails to parse, or if any references a Labels
+// key that classifiers never declares.
+func New(selectors []Selector, classifiers []Classifier, composer Composer) (*Pipeline, error) {
+	p := &Pipeline{
+		selectors:   selectors,
+		classifiers: classifiers,
+		composer:    composer,
+	}
+
+	var err error
+	if p.nameTmpl, err = parseTemplate("name", composer.NameTemplate); err != nil {
+		return nil, err
+	}
+	if p.descTmpl, err = parseTemplate("description", composer.DescriptionTemplate); err != nil {
+		return nil, err
+	}
+	if p.aggTmpl, err = parseTemplate("aggregation", composer.AggregationTemplate); err != nil {
+		return nil, err
+	}
+
+	known := map[string]struct{}{"Instrument": {}}
+	for _, c := range classifiers {
+		for _, k := range c.Keys {
+			known[k] = struct{}{}
+		}
+	}
+	for _, t := range []*template.Template{p.nameTmpl, p.descTmpl, p.aggTmpl} {
+		for _, ref := range templateRefs(t) {
+			if _, ok := known[ref]; !ok {
+				return nil, fmt.Errorf("%w: %q", errUndefinedLabel, ref)
+			}
+		}
+	}
+
+	return p, nil
+}
+
+func parseTemplate(name, src string) (*template.Template, error) {
+	if src == "" {
+		return nil, nil
+	}
+	t, err := template.New(name).Parse(src)
+	if err != nil {
+		return nil, fmt.Errorf("viewpipeline: parsing %s template: %w", name, err)
+	}
+	return t, nil
+}
+
+// templateRefs returns the distinct top-level identifiers t's template
+// actions reference via a field node (e.g. "tier" for "{{.tier}}" and
+// "Instrument" for "{{.Instrument.Name}}" — only the first identifier of a
+// dotted chain is a label-bag reference, the rest are fields on it). It walks the parsed
+// template's action tree rather than scanning raw source, so a multi-segment
+// chain like ".Instrument.Name" correctly yields only "Instrument".
+func templateRefs(t *template.Template) []string {
+	if t == nil || t.Tree == nil {
+		return nil
+	}
+
+	seen := map[string]struct{}{}
+	var refs []string
+	record := func(ident string) {
+		if _, ok := seen[ident]; ok {
+			return
+		}
+		seen[ident] = struct{}{}
+		refs = append(refs, ident)
+	}
+
+	var walk func(parse.Node)
+	walk = func(n parse.Node) {
+		switch x := n.(type) {
+		case nil:
+		case *parse.ListNode:
+			for _, c := range x.Nodes {
+				walk(c)
+			}
+		case *parse.ActionNode:
+			walk(x.Pipe)
+		case *parse.PipeNode:
+			if x == nil {
+				return
+			}
+			for _, cmd := range x.Cmds {
+				walk(cmd)
+			}
+		case *parse.CommandNode:
+			for _, a := range x.Args {
+				walk(a)
+			}
+		case *parse.FieldNode:
+			if len(x.Ident) > 0 {
+				record(x.Ident[0])
+			}
+		case *parse.IfNode:
+			walk(x.Pipe)
+			walk(x.List)
+			walk(x.ElseList)
+		case *parse.RangeNode:
+			walk(x.Pipe)
+			walk(x.List)
+			walk(x.ElseList)
+		case *parse.WithNode:
+			walk(x.Pipe)
+			walk(x.List)
+			walk(x.ElseList)
+		}
+	}
+	walk(t.Tree.Root)
+
+	return refs
+}
+
+// aggregationFromName resolves an AggregationTemplate's rendered output
+// into an aggregation.Aggregation. The empty string and "default" both
+// mean "use the Reader's default aggregation" (a nil Aggregation).
+func aggregationFromName(name string) (aggregation.Aggregation, bool) {
+	switch name {
+	case "", "default":
+		return nil, true
+	case "drop":
+		return aggregation.Drop{}, true
+	default:
+		return nil, false
+	}
+}
+
+// templateData is the value Composer templates are executed against: its
+// Labels keys are reachable directly (e.g. "{{.tier}}") and the matched
+// Instrument is reachable under "Instrument" (e.g. "{{.Instrument.Name}}").
+func templateData(i metric.Instrument, labels Labels) map[string]any {
+	data := make(map[string]any, len(labels)+1)
+	for k, v := range labels {
+		data[k] = v
+	}
+	data["Instrument"] = i
+	return data
+}
+
+func execTemplate(t *template.Template, data map[string]any) (string, error) {
+	if t == nil {
+		return "", nil
+	}
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// View compiles p into a metric.View, suitable for use with
+// metric.WithView.
+func (p *Pipeline) View() metric.View {
+	return func(i metric.Instrument) (metric.Stream, bool) {
+		for _, sel := range p.selectors {
+			if !sel(i) {
+				return metric.Stream{}, false
+			}
+		}
+
+		labels := Labels{}
+		for _, c := range p.classifiers {
+			labels = c.Classify(i, labels)
+		}
+
+		data := templateData(i, labels)
+		stream := metric.Stream{Name: i.Name, Description: i.Description}
+
+		if name, err := execTemplate(p.nameTmpl, data); err != nil {
+			otel.Handle(fmt.Errorf("viewpipeline: rendering name: %w", err))
+		} else if name != "" {
+			stream.Name = name
+		}
+
+		if desc, err := execTemplate(p.descTmpl, data); err != nil {
+			otel.Handle(fmt.Errorf("viewpipeline: rendering description: %w", err))
+		} else if desc != "" {
+			stream.Description = desc
+		}
+
+		if aggName, err := execTemplate(p.aggTmpl, data); err != nil {
+			otel.Handle(fmt.Errorf("viewpipeline: rendering aggregation: %w", err))
+		} else if agg, ok := aggregationFromName(aggName); ok {
+			stream.Aggregation = agg
+		} else {
+			otel.Handle(fmt.Errorf("viewpipeline: unrecognized aggregation %q", aggName))
+		}
+
+		return stream, true
+	}
+}
+
+// StepKind identifies which Pipeline stage a Step reports on.
+type StepKind int
+
+const (
+	// StepSelect reports the outcome of a Selector.
+	StepSelect StepKind = iota
+	// StepClassify reports the Labels after a Classifier has run.
+	StepClassify
+	// StepCompose reports the final rendered Stream.
+	StepCompose
+)
+
+// Step is one stage of evaluating an Instrument against a Pipeline, as
+// returned by Pipeline.Explain.
+type Step struct {
+	Kind   StepKind
+	Detail string
+	Labels Labels
+}
+
+// Explain walks i through p the same way View does, but returns a Step for
+// every stage it runs. Like View, it stops after the first Selector that
+// does not match, so the last Step is then that Selector's. It is meant for
+// debugging why an Instrument was, or was not, matched and how it was
+// transformed.
+func (p *Pipeline) Explain(i metric.Instrument) []Step {
+	var steps []Step
+
+	for idx, sel := range p.selectors {
+		matched := sel(i)
+		detail := fmt.Sprintf("selector %d: matched=%t", idx, matched)
+		steps = append(steps, Step{Kind: StepSelect, Detail: detail})
+		if !matched {
+			return steps
+		}
+	}
+
+	labels := Labels{}
+	for idx, c := range p.classifiers {
+		labels = c.Classify(i, labels)
+		steps = append(steps, Step{
+			Kind:   StepClassify,
+			Detail: fmt.Sprintf("classifier %d", idx),
+			Labels: labels.Clone(),
+		})
+	}
+
+	data := templateData(i, labels)
+	name, nameErr := execTemplate(p.nameTmpl, data)
+	desc, descErr := execTemplate(p.descTmpl, data)
+	aggName, aggErr := execTemplate(p.aggTmpl, data)
+
+	detail := fmt.Sprintf("name=%q description=%q aggregation=%q", name, desc, aggName)
+	if err := firstErr(nameErr, descErr, aggErr); err != nil {
+		detail = fmt.Sprintf("%s error=%s", detail, err)
+	} else if _, ok := aggregationFromName(aggName); !ok {
+		detail = fmt.Sprintf("%s error=unrecognized aggregation %q", detail, aggName)
+	}
+	steps = append(steps, Step{Kind: StepCompose, Detail: detail, Labels: labels.Clone()})
+
+	return steps
+}
+
+func firstErr(errs ...error) error {
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
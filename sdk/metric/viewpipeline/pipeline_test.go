@@ -0,0 +1,142 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package viewpipeline // import "go.opentelemetry.io/otel/sdk/metric/viewpipeline"
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/otel/sdk/instrumentation"
+	"go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/aggregation"
+)
+
+func instrumentationScope(name string) instrumentation.Scope {
+	return instrumentation.Scope{Name: name}
+}
+
+func netHTTPSelector(i metric.Instrument) bool {
+	return strings.HasPrefix(i.Scope.Name, "net/http")
+}
+
+func tierClassifier(i metric.Instrument, l Labels) Labels {
+	out := l.Clone()
+	if strings.Contains(i.Name, "duration") {
+		out["tier"] = "hot"
+	} else {
+		out["tier"] = "cold"
+	}
+	return out
+}
+
+func cardinalityClassifier(i metric.Instrument, l Labels) Labels {
+	out := l.Clone()
+	if strings.HasSuffix(i.Name, "requests") {
+		out["cardinality"] = "high"
+	} else {
+		out["cardinality"] = "low"
+	}
+	return out
+}
+
+func testPipeline(t *testing.T) *Pipeline {
+	t.Helper()
+	p, err := New(
+		[]Selector{netHTTPSelector},
+		[]Classifier{
+			{Keys: []string{"tier"}, Classify: tierClassifier},
+			{Keys: []string{"cardinality"}, Classify: cardinalityClassifier},
+		},
+		Composer{
+			NameTemplate:        "{{.tier}}.{{.Instrument.Name}}",
+			AggregationTemplate: `{{if eq .cardinality "high"}}drop{{else}}default{{end}}`,
+		},
+	)
+	require.NoError(t, err)
+	return p
+}
+
+func TestPipelineView(t *testing.T) {
+	v := testPipeline(t).View()
+
+	t.Run("SelectorRejects", func(t *testing.T) {
+		_, matches := v(metric.Instrument{Name: "foo", Scope: instrumentationScope("other")})
+		assert.False(t, matches)
+	})
+
+	t.Run("NameFromClassifiers", func(t *testing.T) {
+		stream, matches := v(metric.Instrument{Name: "requests", Scope: instrumentationScope("net/http")})
+		require.True(t, matches)
+		assert.Equal(t, "cold.requests", stream.Name)
+		assert.Equal(t, aggregation.Drop{}, stream.Aggregation)
+	})
+
+	t.Run("HotTierDefaultAggregation", func(t *testing.T) {
+		stream, matches := v(metric.Instrument{Name: "duration", Scope: instrumentationScope("net/http")})
+		require.True(t, matches)
+		assert.Equal(t, "hot.duration", stream.Name)
+		assert.Nil(t, stream.Aggregation)
+	})
+}
+
+func TestPipelineExplain(t *testing.T) {
+	p := testPipeline(t)
+
+	t.Run("StopsAtSelector", func(t *testing.T) {
+		steps := p.Explain(metric.Instrument{Name: "foo", Scope: instrumentationScope("other")})
+		require.Len(t, steps, 1)
+		assert.Equal(t, StepSelect, steps[0].Kind)
+	})
+
+	t.Run("RunsAllStages", func(t *testing.T) {
+		steps := p.Explain(metric.Instrument{Name: "requests", Scope: instrumentationScope("net/http")})
+		require.Len(t, steps, 4)
+		assert.Equal(t, StepSelect, steps[0].Kind)
+		assert.Equal(t, StepClassify, steps[1].Kind)
+		assert.Equal(t, StepClassify, steps[2].Kind)
+		assert.Equal(t, StepCompose, steps[3].Kind)
+		assert.Equal(t, "high", steps[3].Labels["cardinality"])
+	})
+}
+
+func TestNewAllowsMultiSegmentInstrumentField(t *testing.T) {
+	// A dotted chain like ".Instrument.Name" must only be checked against
+	// its first identifier ("Instrument"); "Name" is a field on it, not a
+	// label reference, and must not need its own Classifier.
+	p, err := New(nil, nil, Composer{NameTemplate: "{{.Instrument.Name}}-{{.Instrument.Scope.Name}}"})
+	require.NoError(t, err)
+
+	stream, matches := p.View()(metric.Instrument{Name: "foo", Scope: instrumentationScope("bar")})
+	require.True(t, matches)
+	assert.Equal(t, "foo-bar", stream.Name)
+}
+
+func TestNewRejectsUndefinedLabel(t *testing.T) {
+	_, err := New(
+		nil,
+		[]Classifier{{Keys: []string{"tier"}, Classify: tierClassifier}},
+		Composer{NameTemplate: "{{.unit}}.{{.Instrument.Name}}"},
+	)
+	require.Error(t, err)
+	assert.ErrorIs(t, err, errUndefinedLabel)
+}
+
+func TestNewRejectsInvalidTemplate(t *testing.T) {
+	_, err := New(nil, nil, Composer{NameTemplate: "{{.Instrument.Name"})
+	require.Error(t, err)
+}